@@ -0,0 +1,120 @@
+package common
+
+import "engo.io/ecs"
+
+// AnimationFrame is one <frame> of a TMX tileset tile's <animation>
+// element. TileID is the map-global GID of the tile to display during
+// this frame (the loader resolves it from the tileset-local id TMX gives
+// by adding the owning tileset's firstgid, the same way ordinary GIDs are
+// resolved elsewhere in this package).
+type AnimationFrame struct {
+	TileID     int
+	DurationMS int
+}
+
+// TileAnimation is the frame list parsed from a tileset tile's
+// <animation> element. A *TileAnimation is shared by every tile instance
+// on the map with the same GID, so all of them advance in lockstep.
+type TileAnimation struct {
+	Frames []AnimationFrame
+}
+
+// animationState is the live playback position for one TileAnimation. It
+// is shared by every placed tile instance with that animation's GID;
+// instances contribute the *tile itself (not just its Texture) so the
+// system can reapply that instance's own flip flags when it swaps in a
+// new frame's viewport.
+type animationState struct {
+	anim    *TileAnimation
+	frames  []*Texture // resolved once, frames[i] is the Texture for anim.Frames[i] (nil if unresolvable)
+	elapsed float32
+	current int
+
+	instances []*tile
+}
+
+// advance steps the animation forward by dt seconds and reports whether
+// the current frame changed.
+func (s *animationState) advance(dt float32) bool {
+	if len(s.anim.Frames) == 0 {
+		return false
+	}
+
+	s.elapsed += dt * 1000
+	changed := false
+	for s.elapsed >= float32(s.anim.Frames[s.current].DurationMS) {
+		s.elapsed -= float32(s.anim.Frames[s.current].DurationMS)
+		s.current = (s.current + 1) % len(s.anim.Frames)
+		changed = true
+	}
+	return changed
+}
+
+// AnimatedTileSystem advances every animated tile's current frame by
+// time, rather than by render call, so every instance of a given animated
+// GID on the map stays in sync no matter how many times it appears.
+type AnimatedTileSystem struct {
+	states []*animationState
+}
+
+var _ ecs.System = (*AnimatedTileSystem)(nil)
+
+// NewAnimatedTileSystem builds the System driving every animated tile
+// placed across lvl's TileLayers. tileset is the flat, GID-ordered slice
+// produced by createTileset, used to resolve each AnimationFrame's GID to
+// its Texture.
+func NewAnimatedTileSystem(lvl *Level, tileset []*tile) *AnimatedTileSystem {
+	s := &AnimatedTileSystem{}
+	states := make(map[*TileAnimation]*animationState)
+
+	for _, layer := range lvl.TileLayers {
+		for _, t := range layer.Tiles {
+			if t == nil || t.Animation == nil {
+				continue
+			}
+
+			state, ok := states[t.Animation]
+			if !ok {
+				state = &animationState{anim: t.Animation}
+				for _, frame := range t.Animation.Frames {
+					var img *Texture // stays nil if frame's GID is outside tileset
+					if tileIdx := frame.TileID - 1; tileIdx >= 0 && tileIdx < len(tileset) {
+						img = tileset[tileIdx].Image
+					}
+					state.frames = append(state.frames, img)
+				}
+				states[t.Animation] = state
+				s.states = append(s.states, state)
+			}
+
+			state.instances = append(state.instances, t)
+		}
+	}
+
+	return s
+}
+
+// Update advances every tracked animation by dt and, for each one whose
+// frame just changed, mutates every instance's per-instance *Texture
+// viewport to match the new frame, reapplying that instance's own flip
+// flags so an animated tile that is also flipped doesn't snap back to its
+// unflipped orientation on every frame swap.
+func (s *AnimatedTileSystem) Update(dt float32) {
+	for _, state := range s.states {
+		if !state.advance(dt) || len(state.frames) == 0 {
+			continue
+		}
+
+		frame := state.frames[state.current]
+		if frame == nil {
+			continue
+		}
+		for _, inst := range state.instances {
+			inst.Image.viewport = flipViewport(frame.viewport, inst.FlipHorizontal, inst.FlipVertical, inst.FlipDiagonal)
+		}
+	}
+}
+
+// Remove satisfies ecs.System; AnimatedTileSystem tracks tiles rather than
+// entities, so there is nothing to look up an entity by.
+func (s *AnimatedTileSystem) Remove(entity ecs.BasicEntity) {}
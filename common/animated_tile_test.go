@@ -0,0 +1,65 @@
+package common
+
+import (
+	"testing"
+
+	"engo.io/engo"
+)
+
+// newAnimatedTestLevel builds a Level with one 2x1 TileLayer and a
+// tileset of three frames, where GID 1 carries a 2-frame animation
+// cycling between GIDs 2 and 3.
+func newAnimatedTestLevel() (*Level, []*tile) {
+	lvl := &Level{Orientation: "orthogonal", TileWidth: 16, TileHeight: 16, width: 2, height: 1}
+
+	frame1 := &Texture{viewport: engo.AABB{Min: engo.Point{X: 0, Y: 0}, Max: engo.Point{X: 1, Y: 1}}}
+	frame2 := &Texture{viewport: engo.AABB{Min: engo.Point{X: 1, Y: 1}, Max: engo.Point{X: 2, Y: 2}}}
+	tileset := []*tile{
+		{Image: frame1},
+		{Image: frame1},
+		{Image: frame2},
+	}
+	tileset[0].Animation = &TileAnimation{Frames: []AnimationFrame{
+		{TileID: 2, DurationMS: 100},
+		{TileID: 3, DurationMS: 100},
+	}}
+
+	plain := &tile{Image: frame1}
+	animated := &tile{Image: frame1, Animation: tileset[0].Animation, FlipHorizontal: true}
+	layer := &TileLayer{Width: 2, Height: 1, Tiles: []*tile{plain, animated}, Level: lvl}
+	lvl.TileLayers = []*TileLayer{layer}
+
+	return lvl, tileset
+}
+
+func TestAnimatedTileSystemAdvancesFrame(t *testing.T) {
+	lvl, tileset := newAnimatedTestLevel()
+	sys := NewAnimatedTileSystem(lvl, tileset)
+
+	animated := lvl.TileLayers[0].Tiles[1]
+	before := animated.Image.viewport
+
+	sys.Update(0.05) // 50ms, not yet a full 100ms frame
+	if animated.Image.viewport != before {
+		t.Errorf("viewport changed before the frame duration elapsed")
+	}
+
+	sys.Update(0.06) // pushes elapsed past 100ms
+	if animated.Image.viewport == before {
+		t.Errorf("viewport did not change after the frame duration elapsed")
+	}
+}
+
+func TestAnimatedTileSystemPreservesFlip(t *testing.T) {
+	lvl, tileset := newAnimatedTestLevel()
+	sys := NewAnimatedTileSystem(lvl, tileset)
+
+	animated := lvl.TileLayers[0].Tiles[1]
+	sys.Update(0.15) // 150ms: past frame 0's 100ms duration, into frame 1 (GID 3)
+
+	frame := tileset[2].Image.viewport // GID 3's raw, unflipped viewport
+	want := flipViewport(frame, true, false, false)
+	if animated.Image.viewport != want {
+		t.Errorf("got viewport %v, want flipped viewport %v (flip flag was lost on frame swap)", animated.Image.viewport, want)
+	}
+}
@@ -0,0 +1,169 @@
+package common
+
+import "engo.io/engo"
+
+// ChunkSize is the width and height, in tiles, of each TileChunk. Tiled
+// emits 16x16 chunks for infinite maps, so that's what ChunkedTileLayer
+// stores.
+const ChunkSize = 16
+
+// ChunkCoord identifies a TileChunk by its position in the chunk grid
+// (i.e. map coordinates divided by ChunkSize), not the tile grid.
+type ChunkCoord struct {
+	X, Y int
+}
+
+// TileChunk holds one ChunkSize x ChunkSize block of tiles belonging to a
+// ChunkedTileLayer.
+type TileChunk struct {
+	// Coord is this chunk's position in the chunk grid
+	Coord ChunkCoord
+	// Tiles is the chunk's own ChunkSize x ChunkSize, row-major tile grid
+	Tiles []*tile
+}
+
+// tileAt returns the tile at the given tile-local coordinates within the
+// chunk (0 <= x,y < ChunkSize), or nil if that slot was never painted.
+func (c *TileChunk) tileAt(x, y int) *tile {
+	if x < 0 || y < 0 || x >= ChunkSize || y >= ChunkSize {
+		return nil
+	}
+	idx := x + y*ChunkSize
+	if idx >= len(c.Tiles) {
+		return nil
+	}
+	return c.Tiles[idx]
+}
+
+// Bounds returns the chunk's screen-space AABB, assuming an orthogonal
+// tile grid of the given tile size.
+func (c *TileChunk) Bounds(tileWidth, tileHeight float32) engo.AABB {
+	minX := float32(c.Coord.X*ChunkSize) * tileWidth
+	minY := float32(c.Coord.Y*ChunkSize) * tileHeight
+	return engo.AABB{
+		Min: engo.Point{X: minX, Y: minY},
+		Max: engo.Point{X: minX + ChunkSize*tileWidth, Y: minY + ChunkSize*tileHeight},
+	}
+}
+
+// ChunkedTileLayer is a TileLayer variant for Tiled's "infinite" maps:
+// instead of one dense tile grid spanning the whole level, tiles are
+// stored per fixed-size chunk, and chunks Tiled never painted are never
+// allocated. Nothing in this package builds one from TMX data: a
+// ChunkedTileLayer is created with NewChunkedTileLayer, filled with
+// SetChunk, and added to Level.ChunkedTileLayers by the caller.
+type ChunkedTileLayer struct {
+	// Name defines the name of the tile layer given in the TMX XML / Tiled
+	Name string
+	// Chunks holds every non-empty chunk, keyed by its chunk-grid position
+	Chunks map[ChunkCoord]*TileChunk
+	// Level contains a link back to the level we are part of
+	*Level
+	// Properties represents properties about this layer
+	Properties map[string]Property
+}
+
+// NewChunkedTileLayer creates an empty ChunkedTileLayer belonging to lvl.
+func NewChunkedTileLayer(name string, lvl *Level) *ChunkedTileLayer {
+	return &ChunkedTileLayer{
+		Name:   name,
+		Chunks: make(map[ChunkCoord]*TileChunk),
+		Level:  lvl,
+	}
+}
+
+// SetChunk installs (or replaces) the chunk at coord.
+func (l *ChunkedTileLayer) SetChunk(coord ChunkCoord, tiles []*tile) {
+	l.Chunks[coord] = &TileChunk{Coord: coord, Tiles: tiles}
+}
+
+// GetTile returns the tile at map coordinate (x, y), dispatching to the
+// chunk that owns it. It returns nil both for coordinates whose chunk was
+// never painted in Tiled and for coordinates inside a chunk that weren't
+// painted individually; allocating a chunk on miss is deliberately not
+// done, since most of an infinite map's chunk grid is typically empty.
+func (l *ChunkedTileLayer) GetTile(x, y int) *tile {
+	coord, localX, localY := chunkCoordFor(x, y)
+	chunk, ok := l.Chunks[coord]
+	if !ok {
+		return nil
+	}
+	return chunk.tileAt(localX, localY)
+}
+
+// chunkCoordFor returns the chunk owning map coordinate (x, y), plus (x, y)
+// expressed as coordinates local to that chunk.
+func chunkCoordFor(x, y int) (coord ChunkCoord, localX, localY int) {
+	cx := floorDiv(x, ChunkSize)
+	cy := floorDiv(y, ChunkSize)
+	return ChunkCoord{X: cx, Y: cy}, x - cx*ChunkSize, y - cy*ChunkSize
+}
+
+// floorDiv is integer division that rounds toward negative infinity
+// rather than toward zero (as Go's / operator does), so chunk coordinates
+// stay consistent on the negative side of the map origin.
+func floorDiv(a, b int) int {
+	q := a / b
+	if a%b != 0 && (a < 0) != (b < 0) {
+		q--
+	}
+	return q
+}
+
+// VisibleChunks returns every chunk whose bounds intersect view, so the
+// renderer can iterate just the chunks the camera can currently see
+// instead of the whole, potentially unbounded, map.
+func (l *ChunkedTileLayer) VisibleChunks(view engo.AABB) []*TileChunk {
+	tw := float32(l.Level.TileWidth)
+	th := float32(l.Level.TileHeight)
+
+	var visible []*TileChunk
+	for _, chunk := range l.Chunks {
+		if aabbIntersects(chunk.Bounds(tw, th), view) {
+			visible = append(visible, chunk)
+		}
+	}
+	return visible
+}
+
+func aabbIntersects(a, b engo.AABB) bool {
+	return a.Min.X < b.Max.X && a.Max.X > b.Min.X &&
+		a.Min.Y < b.Max.Y && a.Max.Y > b.Min.Y
+}
+
+// chunkedBounds computes the union of every populated chunk's bounds
+// across all of the level's ChunkedTileLayers, used by Level.Bounds when
+// Infinite is set.
+func (l *Level) chunkedBounds() engo.AABB {
+	var bounds engo.AABB
+	first := true
+
+	for _, layer := range l.ChunkedTileLayers {
+		tw := float32(l.TileWidth)
+		th := float32(l.TileHeight)
+		for _, chunk := range layer.Chunks {
+			b := chunk.Bounds(tw, th)
+			if first {
+				bounds = b
+				first = false
+				continue
+			}
+			if b.Min.X < bounds.Min.X {
+				bounds.Min.X = b.Min.X
+			}
+			if b.Min.Y < bounds.Min.Y {
+				bounds.Min.Y = b.Min.Y
+			}
+			if b.Max.X > bounds.Max.X {
+				bounds.Max.X = b.Max.X
+			}
+			if b.Max.Y > bounds.Max.Y {
+				bounds.Max.Y = b.Max.Y
+			}
+		}
+	}
+
+	bounds.Min.Add(l.Offset)
+	bounds.Max.Add(l.Offset)
+	return bounds
+}
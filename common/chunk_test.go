@@ -0,0 +1,80 @@
+package common
+
+import (
+	"testing"
+
+	"engo.io/engo"
+)
+
+func TestChunkedTileLayerGetTile(t *testing.T) {
+	lvl := &Level{TileWidth: 16, TileHeight: 16, Infinite: true}
+	layer := NewChunkedTileLayer("ground", lvl)
+
+	tiles := make([]*tile, ChunkSize*ChunkSize)
+	tiles[3+2*ChunkSize] = &tile{}
+	layer.SetChunk(ChunkCoord{X: 0, Y: 0}, tiles)
+
+	if got := layer.GetTile(3, 2); got == nil {
+		t.Errorf("expected a tile at (3,2) in the painted chunk")
+	}
+	if got := layer.GetTile(0, 0); got != nil {
+		t.Errorf("expected nil for an unpainted slot in a painted chunk, got %v", got)
+	}
+	if got := layer.GetTile(ChunkSize+1, 0); got != nil {
+		t.Errorf("expected nil for a coordinate whose chunk was never painted, got %v", got)
+	}
+}
+
+func TestChunkedTileLayerNegativeCoords(t *testing.T) {
+	lvl := &Level{TileWidth: 16, TileHeight: 16, Infinite: true}
+	layer := NewChunkedTileLayer("ground", lvl)
+
+	tiles := make([]*tile, ChunkSize*ChunkSize)
+	localX, localY := ChunkSize-1, ChunkSize-1
+	tiles[localX+localY*ChunkSize] = &tile{}
+	layer.SetChunk(ChunkCoord{X: -1, Y: -1}, tiles)
+
+	if got := layer.GetTile(-1, -1); got == nil {
+		t.Errorf("expected a tile at map coordinate (-1,-1), got nil")
+	}
+}
+
+func TestVisibleChunksIntersection(t *testing.T) {
+	lvl := &Level{TileWidth: 16, TileHeight: 16, Infinite: true}
+	layer := NewChunkedTileLayer("ground", lvl)
+
+	layer.SetChunk(ChunkCoord{X: 0, Y: 0}, make([]*tile, ChunkSize*ChunkSize))
+	layer.SetChunk(ChunkCoord{X: 10, Y: 10}, make([]*tile, ChunkSize*ChunkSize))
+
+	view := engo.AABB{Min: engo.Point{X: 0, Y: 0}, Max: engo.Point{X: 20, Y: 20}}
+	visible := layer.VisibleChunks(view)
+
+	if len(visible) != 1 {
+		t.Fatalf("got %d visible chunks, want 1", len(visible))
+	}
+	if visible[0].Coord != (ChunkCoord{X: 0, Y: 0}) {
+		t.Errorf("got visible chunk %v, want (0,0)", visible[0].Coord)
+	}
+}
+
+func TestLevelBoundsUnionOfChunks(t *testing.T) {
+	lvl := &Level{TileWidth: 16, TileHeight: 16, Infinite: true}
+	layer := NewChunkedTileLayer("ground", lvl)
+	lvl.ChunkedTileLayers = []*ChunkedTileLayer{layer}
+
+	layer.SetChunk(ChunkCoord{X: 0, Y: 0}, make([]*tile, ChunkSize*ChunkSize))
+	layer.SetChunk(ChunkCoord{X: 2, Y: -1}, make([]*tile, ChunkSize*ChunkSize))
+
+	bounds := lvl.Bounds()
+
+	wantMinX := float32(2 * ChunkSize * 16)
+	wantMinY := float32(-1 * ChunkSize * 16)
+	if bounds.Min.X != 0 || bounds.Min.Y != wantMinY {
+		t.Errorf("got bounds.Min %v, want (0, %v)", bounds.Min, wantMinY)
+	}
+	wantMaxX := wantMinX + ChunkSize*16
+	wantMaxY := float32(ChunkSize * 16)
+	if bounds.Max.X != wantMaxX || bounds.Max.Y != wantMaxY {
+		t.Errorf("got bounds.Max %v, want (%v, %v)", bounds.Max, wantMaxX, wantMaxY)
+	}
+}
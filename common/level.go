@@ -27,16 +27,27 @@ type Level struct {
 	TileWidth int
 	// TileHeight defines the height of each tile in the level
 	TileHeight int
+	// HexSideLength is the TMX hexsidelength attribute, used by the
+	// hexagonal-x/hexagonal-y orientations to compute row/column spacing.
+	HexSideLength int
 	// NextObjectId is the next free Object ID defined by Tiled
 	NextObjectId int
+	// Infinite is true for TMX maps with infinite="1", whose tile layers
+	// are stored as ChunkedTileLayers rather than TileLayers.
+	Infinite bool
 	// TileLayers contains all TileLayer of the level
 	TileLayers []*TileLayer
+	// ChunkedTileLayers contains all ChunkedTileLayer of the level, used
+	// instead of TileLayers when Infinite is set
+	ChunkedTileLayers []*ChunkedTileLayer
 	// ImageLayers contains all ImageLayer of the level
 	ImageLayers []*ImageLayer
 	// ObjectLayers contains all ObjectLayer of the level
 	ObjectLayers []*ObjectLayer
 	// Properties represents properties about this map.
 	Properties map[string]Property
+	// orientation is the adapter resolved from Orientation by setupOrientation
+	orientation OrientationAdapter
 }
 
 // MapToPosition maps a map coordinate with subtile accuracy and return position
@@ -81,84 +92,37 @@ func (l *TileLayer)GetTile(p *engo.Point) (*tile) {
 }
 
 
-// setupOrientation is a function to setup defualt helper functions based on the
-// level orientation as defined by tmx.
-func (lvl *Level)setupOrientation() error {
-	// tile (half-)widths for isometric tilesets.
-	tw := float32(lvl.TileWidth)
-	th := float32(lvl.TileHeight)
-	hw := float32(lvl.TileWidth/2)
-	hh := float32(lvl.TileHeight/2)
-
-	// Do the string comparisons once and setup helper functions
-	if lvl.Orientation == "orthogonal" {
-		lvl.MapToPoint = func(m *engo.Point) (*engo.Point) {
-			m.X = m.X * tw
-			m.Y = m.Y * th
-			return m
-		}
-		lvl.PointToMap = func(p *engo.Point) (*engo.Point) {
-			p.X = p.X / tw
-			p.Y = p.Y / th
-			return p
-		}
-		lvl.MapMaxBounds = func() (*engo.Point) {
-			return &engo.Point{
-				float32(lvl.TileWidth * lvl.width),
-				float32(lvl.TileHeight * lvl.height),
-			}
-		}
-	} else if lvl.Orientation == "isometric" {
-		lvl.MapToPoint = func(m *engo.Point) (*engo.Point) {
-			m.X = (m.X - m.Y) * hw
-			m.Y = (m.X + m.Y) * hh
-			return m
-		}
-		lvl.PointToMap = func(p *engo.Point) (*engo.Point) {
-			p.X = (p.X + p.Y) / tw
-			p.Y = (p.Y - p.X) / th
-			return p
-		}
-		lvl.MapMaxBounds = func() (*engo.Point) {
-			return &engo.Point{
-				float32(lvl.TileWidth * lvl.width) + float32(lvl.TileWidth/2),
-				float32(lvl.TileHeight/2 * lvl.height) + float32(lvl.TileHeight/2),
-			}
-		}
-	} else if lvl.Orientation == "staggered" {
-		lvl.MapToPoint = func(m *engo.Point) (*engo.Point) {
-			staggerX := float32(0) // no offset on even rows
-			if int(m.Y)%2 == 1 {   // odd row?
-				staggerX = hw
-			}
-			m.X = (m.X * tw) + staggerX
-			m.Y = m.Y * hh
-			return m
-		}
-		lvl.PointToMap = func(p *engo.Point) (*engo.Point) {
-			Y := p.Y
-			p.Y = (p.Y - p.X) / th
-			staggerX := float32(0) // no offset on even rows
-			if int(p.Y)%2 == 1 {   // odd row?
-				staggerX = hw
-			}
-			p.X = (p.X + Y - staggerX) / tw
-			return p
-		}
-		lvl.MapMaxBounds = func() (*engo.Point) {
-			return &engo.Point{
-				float32(lvl.TileWidth * lvl.width) + float32(lvl.TileWidth/2),
-				float32(lvl.TileHeight/2 * lvl.height) + float32(lvl.TileHeight/2),
-			}
-		}
-	} else {
+// setupOrientation resolves lvl.Orientation to an OrientationAdapter via
+// the orientation registry (see RegisterOrientation) and wires up the
+// MapToPoint/PointToMap/MapMaxBounds function pointers from it.
+func (lvl *Level) setupOrientation() error {
+	factory, ok := orientationRegistry[lvl.Orientation]
+	if !ok {
 		return fmt.Errorf(
 			"Level: Unsupported orientation %v",
 			lvl.Orientation)
 	}
+
+	adapter := factory(lvl)
+	lvl.orientation = adapter
+	lvl.MapToPoint = adapter.MapToPoint
+	lvl.PointToMap = adapter.PointToMap
+	lvl.MapMaxBounds = adapter.MapMaxBounds
 	return nil
 }
 
+// TileBounds returns the screen-space AABB of the tile at the given map
+// row and column, using the level's OrientationAdapter. If necessary,
+// calls setupOrientation first to resolve that adapter.
+func (lvl *Level) TileBounds(row, col int) (engo.AABB, error) {
+	if lvl.orientation == nil {
+		if err := lvl.setupOrientation(); err != nil {
+			return engo.AABB{}, err
+		}
+	}
+	return lvl.orientation.TileBounds(row, col), nil
+}
+
 
 // TileLayer contains a list of its tiles plus all default Tiled attributes
 type TileLayer struct {
@@ -190,7 +154,7 @@ type ImageLayer struct {
 	Images []*tile
 }
 
-// ObjectLayer contains a list of its standard objects as well as a list of all its polyline objects
+// ObjectLayer contains a list of its objects, of all shapes
 type ObjectLayer struct {
 	// Name defines the name of the object layer given in the TMX XML / Tiled
 	Name string
@@ -198,15 +162,70 @@ type ObjectLayer struct {
 	OffSetX float32
 	// OffSetY is the parsed Y offset for the object layer
 	OffSetY float32
-	// Objects contains the list of (regular) Object objects
+	// Objects contains the list of Object objects, of every Shape
 	Objects []*Object
-	// PolyObjects contains the list of PolylineObject objects
+	// PolyObjects contains every ShapePolyline object in Objects, as
+	// PolylineObjects. It predates merging polylines into Object and is
+	// kept as a plain field - not a method - so existing source that
+	// ranges over, measures the length of, or builds an ObjectLayer
+	// literal with PolyObjects keeps compiling; it is populated by
+	// NewObjectLayer. Prefer filtering Objects by Shape == ShapePolyline
+	// directly in new code.
 	PolyObjects []*PolylineObject
 	// Properties represents properties about this objectLayer
 	Properties map[string]Property
 }
 
-// Object is a standard TMX object with all its default Tiled attributes
+// NewObjectLayer builds an ObjectLayer from a parsed object list,
+// populating PolyObjects from any ShapePolyline objects found in objects.
+func NewObjectLayer(name string, objects []*Object, properties map[string]Property) *ObjectLayer {
+	l := &ObjectLayer{
+		Name:       name,
+		Objects:    objects,
+		Properties: properties,
+	}
+	for _, o := range objects {
+		if o.Shape != ShapePolyline {
+			continue
+		}
+		l.PolyObjects = append(l.PolyObjects, &PolylineObject{
+			Id:         o.Id,
+			Name:       o.Name,
+			Type:       o.Type,
+			X:          o.X,
+			Y:          o.Y,
+			Points:     o.Points,
+			LineBounds: o.LineBounds,
+		})
+	}
+	return l
+}
+
+// Shape discriminates the geometry an Object carries, mirroring the shapes
+// Tiled itself can attach to a TMX object.
+type Shape int
+
+const (
+	// ShapeRect is a plain rectangle, Tiled's default object shape
+	ShapeRect Shape = iota
+	// ShapeEllipse is an axis-aligned ellipse inscribed in the object's rect
+	ShapeEllipse
+	// ShapePolygon is a closed, arbitrary polygon
+	ShapePolygon
+	// ShapePolyline is an open chain of line segments
+	ShapePolyline
+	// ShapePoint is a single, dimensionless point
+	ShapePoint
+	// ShapeTile is an object carrying a tile gid, rendered like a tile
+	ShapeTile
+	// ShapeText is a text label
+	ShapeText
+)
+
+// Object is a standard TMX object with all its default Tiled attributes.
+// Its exact geometry depends on Shape: Width/Height apply to
+// ShapeRect/ShapeEllipse/ShapeTile/ShapeText, Points/LineBounds/Triangles
+// apply to ShapePolygon/ShapePolyline, and GID applies to ShapeTile.
 type Object struct {
 	// Id is the unique ID of each object defined by Tiled
 	Id int
@@ -214,6 +233,8 @@ type Object struct {
 	Name string
 	// Type contains the string type which was given in Tiled
 	Type string
+	// Shape discriminates this object's geometry
+	Shape Shape
 	// X holds the X float64 coordinate of the object in the map
 	X float64
 	// X holds the X float64 coordinate of the object in the map
@@ -224,9 +245,39 @@ type Object struct {
 	Height int
 	// Properties represents properties about this object
 	Properties map[string]Property
+
+	// Points contains the original, unaltered points string from the TMX
+	// XML for ShapePolygon/ShapePolyline objects.
+	Points string
+	// LineBounds is the list of engo.Line segments generated from Points,
+	// for ShapePolygon/ShapePolyline objects. For ShapePolygon it is
+	// closed (the last point connects back to the first).
+	LineBounds []*engo.Line
+	// Triangles is the ear-clipped triangulation of a ShapePolygon's
+	// interior, used by Contains for point-in-polygon hit-testing.
+	Triangles []engo.Triangle
+
+	// GID is the raw tile GID for a ShapeTile object, flip bits included.
+	GID uint32
+	// FlipHorizontal, FlipVertical and FlipDiagonal are GID's flip flags,
+	// for ShapeTile objects; see SplitGID.
+	FlipHorizontal bool
+	FlipVertical   bool
+	FlipDiagonal   bool
+	// resolvedTile is the tileset *tile a ShapeTile object's GID resolves
+	// to, set by resolveTileObject. A flipped object instead gets its own
+	// *tile whose texture viewport is flipped.
+	resolvedTile *tile
+
+	// Text is the string content of a ShapeText object.
+	Text string
 }
 
-// PolylineObject is a TMX polyline object with all its default Tiled attributes
+// PolylineObject is a TMX polyline object with all its default Tiled
+// attributes. It predates merging polylines into Object; ObjectLayer's
+// PolyObjects field is now built from these by NewObjectLayer for
+// compatibility. Prefer filtering ObjectLayer.Objects by
+// Shape == ShapePolyline directly in new code.
 type PolylineObject struct {
 	// Id is the unique ID of each polyline object defined by Tiled
 	Id int
@@ -244,8 +295,14 @@ type PolylineObject struct {
 	LineBounds []*engo.Line
 }
 
-// Bounds returns the level boundaries as an engo.AABB object
+// Bounds returns the level boundaries as an engo.AABB object. For infinite
+// maps, this is the union of all populated chunk bounds across the
+// level's ChunkedTileLayers rather than width*TileWidth, since an infinite
+// map has no fixed width/height of its own.
 func (l *Level) Bounds() engo.AABB {
+	if l.Infinite {
+		return l.chunkedBounds()
+	}
 	max := l.MapMaxBounds()
 	max.Add(l.Offset)
 	return engo.AABB{
@@ -302,6 +359,16 @@ type tile struct {
 	Image *Texture
 	// Properties represents properties about this map.
 	Properties map[string]Property
+	// FlipHorizontal indicates the tile's texture should be mirrored along the X axis
+	FlipHorizontal bool
+	// FlipVertical indicates the tile's texture should be mirrored along the Y axis
+	FlipVertical bool
+	// FlipDiagonal indicates the tile's texture should be mirrored along its top-left/bottom-right diagonal
+	FlipDiagonal bool
+	// Animation is non-nil if this tile's GID carries a TMX <animation>;
+	// it is shared by every tile instance with the same GID, see
+	// AnimatedTileSystem.
+	Animation *TileAnimation
 }
 
 type Property struct {
@@ -310,22 +377,56 @@ type Property struct {
 }
 
 type tilesheet struct {
-	Image    *TextureResource
-	TileWidth int
+	Image      *TextureResource
+	TileWidth  int
 	TileHeight int
-	Firstgid int
+	Firstgid   int
 	Properties map[string]Property
+	// Animations holds the TileAnimation parsed from any tileset tile's
+	// <animation> child, keyed by that tile's id local to this tilesheet
+	// (i.e. before adding Firstgid).
+	Animations map[int]*TileAnimation
 }
 
 type layer struct {
-	Name        string
-	Width       int
-	Height      int
+	Name   string
+	Width  int
+	Height int
+	// TileMapping holds the raw, row-major GIDs decoded by DecodeLayerData,
+	// flip flags included.
 	TileMapping []uint32
 	// Properties represents properties about this layer
 	Properties map[string]Property
 }
 
+// flipViewport mirrors a texture viewport AABB according to the given
+// Tiled flip flags.
+func flipViewport(vp engo.AABB, flipHorizontal, flipVertical, flipDiagonal bool) engo.AABB {
+	if flipDiagonal {
+		// Diagonal flip transposes the tile about its top-left/bottom-right
+		// diagonal, i.e. it swaps the texture's horizontal and vertical axes.
+		vp.Min.X, vp.Min.Y = vp.Min.Y, vp.Min.X
+		vp.Max.X, vp.Max.Y = vp.Max.Y, vp.Max.X
+	}
+	if flipHorizontal {
+		vp.Min.X, vp.Max.X = vp.Max.X, vp.Min.X
+	}
+	if flipVertical {
+		vp.Min.Y, vp.Max.Y = vp.Max.Y, vp.Min.Y
+	}
+	return vp
+}
+
+// flippedTexture returns a copy of img whose viewport has been mirrored
+// according to the given Tiled flip flags. The underlying tileset texture
+// is shared; only the per-instance viewport AABB is copied, so flipping a
+// tile never allocates a new GL texture.
+func flippedTexture(img *Texture, flipHorizontal, flipVertical, flipDiagonal bool) *Texture {
+	flipped := *img
+	flipped.viewport = flipViewport(flipped.viewport, flipHorizontal, flipVertical, flipDiagonal)
+	return &flipped
+}
+
 func createTileset(lvl *Level, sheets []*tilesheet) []*tile {
 	tileset := make([]*tile, 0)
 
@@ -357,6 +458,20 @@ func createTileset(lvl *Level, sheets []*tilesheet) []*tile {
 					engo.Point{u2, v2},
 				},
 			}
+
+			if anim, ok := sheet.Animations[i]; ok {
+				// Resolve each frame's tileset-local id to a map-global
+				// GID, same as ordinary (non-animated) GIDs elsewhere.
+				resolved := &TileAnimation{Frames: make([]AnimationFrame, len(anim.Frames))}
+				for j, f := range anim.Frames {
+					resolved.Frames[j] = AnimationFrame{
+						TileID:     sheet.Firstgid + f.TileID,
+						DurationMS: f.DurationMS,
+					}
+				}
+				t.Animation = resolved
+			}
+
 			tileset = append(tileset, t)
 		}
 	}
@@ -379,8 +494,20 @@ func createLevelTiles(lvl *Level, layers []*layer, ts []*tile) []*TileLayer {
 				idx := x + i*lvl.width
 				t := &tile{}
 
-				if tileIdx := int(mapping[idx]) - 1; tileIdx >= 0 {
+				id, flipHorizontal, flipVertical, flipDiagonal := SplitGID(mapping[idx])
+				if tileIdx := int(id) - 1; tileIdx >= 0 {
 					t.Image = ts[tileIdx].Image
+					t.Animation = ts[tileIdx].Animation
+					if flipHorizontal || flipVertical || flipDiagonal || t.Animation != nil {
+						// Animated tiles always get their own Texture copy too,
+						// even unflipped, so AnimatedTileSystem can swap this
+						// instance's viewport without touching any other
+						// instance of the same animated GID.
+						t.Image = flippedTexture(t.Image, flipHorizontal, flipVertical, flipDiagonal)
+					}
+					t.FlipHorizontal = flipHorizontal
+					t.FlipVertical = flipVertical
+					t.FlipDiagonal = flipDiagonal
 					tp, _ := lvl.MapToPosition(engo.Point{float32(x), float32(i)})
 					// Align tiles to bottom for oversize tile layering
 					// XXX: bug for unusual draw orders? configurable?
@@ -0,0 +1,254 @@
+package common
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"engo.io/engo"
+	"engo.io/gl"
+)
+
+// parseObjectPoints parses a TMX object's "points" attribute, a
+// whitespace-separated list of "x,y" pairs relative to the object's
+// origin, as found on polygon and polyline objects.
+func parseObjectPoints(points string) ([]engo.Point, error) {
+	fields := strings.Fields(points)
+	out := make([]engo.Point, 0, len(fields))
+	for _, f := range fields {
+		xy := strings.SplitN(f, ",", 2)
+		if len(xy) != 2 {
+			return nil, fmt.Errorf("tmx: malformed point %q in points list %q", f, points)
+		}
+		x, err := strconv.ParseFloat(xy[0], 32)
+		if err != nil {
+			return nil, fmt.Errorf("tmx: malformed point %q: %v", f, err)
+		}
+		y, err := strconv.ParseFloat(xy[1], 32)
+		if err != nil {
+			return nil, fmt.Errorf("tmx: malformed point %q: %v", f, err)
+		}
+		out = append(out, engo.Point{X: float32(x), Y: float32(y)})
+	}
+	return out, nil
+}
+
+// buildLineBounds connects consecutive points into engo.Lines, closing the
+// loop back to the first point when closed is true (polygons) and leaving
+// it open otherwise (polylines).
+func buildLineBounds(points []engo.Point, closed bool) []*engo.Line {
+	if len(points) < 2 {
+		return nil
+	}
+	lines := make([]*engo.Line, 0, len(points))
+	for i := 0; i < len(points)-1; i++ {
+		lines = append(lines, &engo.Line{P1: points[i], P2: points[i+1]})
+	}
+	if closed {
+		lines = append(lines, &engo.Line{P1: points[len(points)-1], P2: points[0]})
+	}
+	return lines
+}
+
+// setPolygonGeometry populates o.Points, o.LineBounds and o.Triangles from
+// a TMX polygon "points" attribute.
+func setPolygonGeometry(o *Object, points string) error {
+	pts, err := parseObjectPoints(points)
+	if err != nil {
+		return err
+	}
+	o.Points = points
+	o.LineBounds = buildLineBounds(pts, true)
+	o.Triangles = triangulate(pts)
+	return nil
+}
+
+// setPolylineGeometry populates o.Points and o.LineBounds from a TMX
+// polyline "points" attribute.
+func setPolylineGeometry(o *Object, points string) error {
+	pts, err := parseObjectPoints(points)
+	if err != nil {
+		return err
+	}
+	o.Points = points
+	o.LineBounds = buildLineBounds(pts, false)
+	return nil
+}
+
+// triangulate ear-clips a simple polygon (no self-intersections) into
+// triangles for hit-testing. It tolerates either winding order.
+func triangulate(points []engo.Point) []engo.Triangle {
+	if len(points) < 3 {
+		return nil
+	}
+
+	// Work on a mutable copy of the index list so we can clip ears off it.
+	idx := make([]int, len(points))
+	for i := range idx {
+		idx[i] = i
+	}
+
+	// Ear clipping assumes CCW winding; flip if the polygon came in CW.
+	if signedArea(points, idx) < 0 {
+		for l, r := 0, len(idx)-1; l < r; l, r = l+1, r-1 {
+			idx[l], idx[r] = idx[r], idx[l]
+		}
+	}
+
+	var triangles []engo.Triangle
+	guard := 0
+	for len(idx) > 3 && guard < len(points)*len(points) {
+		guard++
+		for i := 0; i < len(idx); i++ {
+			a := idx[(i-1+len(idx))%len(idx)]
+			b := idx[i]
+			c := idx[(i+1)%len(idx)]
+
+			if !isConvex(points[a], points[b], points[c]) {
+				continue
+			}
+			if polygonHasPointInTriangle(points, idx, a, b, c) {
+				continue
+			}
+
+			triangles = append(triangles, engo.Triangle{P1: points[a], P2: points[b], P3: points[c]})
+			idx = append(idx[:i], idx[i+1:]...)
+			break
+		}
+	}
+	if len(idx) == 3 {
+		triangles = append(triangles, engo.Triangle{P1: points[idx[0]], P2: points[idx[1]], P3: points[idx[2]]})
+	}
+	return triangles
+}
+
+func signedArea(points []engo.Point, idx []int) float32 {
+	var area float32
+	for i := range idx {
+		a := points[idx[i]]
+		b := points[idx[(i+1)%len(idx)]]
+		area += a.X*b.Y - b.X*a.Y
+	}
+	return area / 2
+}
+
+func isConvex(a, b, c engo.Point) bool {
+	return (b.X-a.X)*(c.Y-a.Y)-(c.X-a.X)*(b.Y-a.Y) > 0
+}
+
+func polygonHasPointInTriangle(points []engo.Point, idx []int, a, b, c int) bool {
+	for _, i := range idx {
+		if i == a || i == b || i == c {
+			continue
+		}
+		if pointInTriangle(points[i], points[a], points[b], points[c]) {
+			return true
+		}
+	}
+	return false
+}
+
+func pointInTriangle(p, a, b, c engo.Point) bool {
+	d1 := sign(p, a, b)
+	d2 := sign(p, b, c)
+	d3 := sign(p, c, a)
+
+	hasNeg := d1 < 0 || d2 < 0 || d3 < 0
+	hasPos := d1 > 0 || d2 > 0 || d3 > 0
+	return !(hasNeg && hasPos)
+}
+
+func sign(p, a, b engo.Point) float32 {
+	return (p.X-b.X)*(a.Y-b.Y) - (a.X-b.X)*(p.Y-b.Y)
+}
+
+// resolveTileObject resolves a ShapeTile object's GID against tileset (the
+// flat, GID-ordered slice produced by createTileset), recording the flip
+// flags and the tile the object should be rendered as.
+func resolveTileObject(o *Object, tileset []*tile) {
+	id, flipHorizontal, flipVertical, flipDiagonal := SplitGID(o.GID)
+	o.FlipHorizontal = flipHorizontal
+	o.FlipVertical = flipVertical
+	o.FlipDiagonal = flipDiagonal
+
+	tileIdx := int(id) - 1
+	if tileIdx < 0 || tileIdx >= len(tileset) {
+		return
+	}
+
+	o.resolvedTile = tileset[tileIdx]
+	img := o.resolvedTile.Image
+	if flipHorizontal || flipVertical || flipDiagonal {
+		img = flippedTexture(img, flipHorizontal, flipVertical, flipDiagonal)
+		o.resolvedTile = &tile{Image: img}
+	}
+	o.Width = int(img.Width())
+	o.Height = int(img.Height())
+}
+
+// Texture returns a ShapeTile object's resolved tile texture, or nil if it
+// is not a ShapeTile object (or its GID failed to resolve).
+func (o *Object) Texture() *gl.Texture {
+	if o.resolvedTile == nil {
+		return nil
+	}
+	return o.resolvedTile.Texture()
+}
+
+// View returns a ShapeTile object's resolved tile viewport's min and max
+// X & Y, mirroring tile.View so ShapeTile objects can be rendered exactly
+// like the tiles they reference.
+func (o *Object) View() (float32, float32, float32, float32) {
+	if o.resolvedTile == nil {
+		return 0, 0, 0, 0
+	}
+	return o.resolvedTile.View()
+}
+
+// Contains reports whether p (in map coordinates) lies within o's
+// geometry, dispatching on o.Shape. ShapePolyline objects have no
+// interior and always return false.
+func (o *Object) Contains(p engo.Point) bool {
+	switch o.Shape {
+	case ShapeEllipse:
+		return pointInEllipse(p, o)
+	case ShapePolygon:
+		local := engo.Point{X: p.X - float32(o.X), Y: p.Y - float32(o.Y)}
+		return pointInTriangles(local, o.Triangles)
+	case ShapePolyline:
+		return false
+	case ShapePoint:
+		const epsilon = 1e-6
+		return float32(p.X) > float32(o.X)-epsilon && float32(p.X) < float32(o.X)+epsilon &&
+			float32(p.Y) > float32(o.Y)-epsilon && float32(p.Y) < float32(o.Y)+epsilon
+	default: // ShapeRect, ShapeTile, ShapeText
+		return pointInRect(p, o)
+	}
+}
+
+func pointInRect(p engo.Point, o *Object) bool {
+	return float64(p.X) >= o.X && float64(p.X) <= o.X+float64(o.Width) &&
+		float64(p.Y) >= o.Y && float64(p.Y) <= o.Y+float64(o.Height)
+}
+
+func pointInEllipse(p engo.Point, o *Object) bool {
+	rx := float64(o.Width) / 2
+	ry := float64(o.Height) / 2
+	if rx == 0 || ry == 0 {
+		return false
+	}
+	cx := o.X + rx
+	cy := o.Y + ry
+	dx := (float64(p.X) - cx) / rx
+	dy := (float64(p.Y) - cy) / ry
+	return dx*dx+dy*dy <= 1
+}
+
+func pointInTriangles(p engo.Point, triangles []engo.Triangle) bool {
+	for _, t := range triangles {
+		if pointInTriangle(p, t.P1, t.P2, t.P3) {
+			return true
+		}
+	}
+	return false
+}
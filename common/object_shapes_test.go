@@ -0,0 +1,98 @@
+package common
+
+import (
+	"testing"
+
+	"engo.io/engo"
+)
+
+func TestTriangulateSquare(t *testing.T) {
+	square := []engo.Point{{X: 0, Y: 0}, {X: 10, Y: 0}, {X: 10, Y: 10}, {X: 0, Y: 10}}
+	triangles := triangulate(square)
+
+	if len(triangles) != 2 {
+		t.Fatalf("got %d triangles, want 2", len(triangles))
+	}
+	if !pointInTriangles(engo.Point{X: 5, Y: 5}, triangles) {
+		t.Errorf("expected the square's center to be inside its triangulation")
+	}
+	if pointInTriangles(engo.Point{X: 20, Y: 20}, triangles) {
+		t.Errorf("expected a point outside the square to be outside its triangulation")
+	}
+}
+
+func TestTriangulateConcavePolygon(t *testing.T) {
+	// An "L" shape: concave at (5,5).
+	l := []engo.Point{
+		{X: 0, Y: 0}, {X: 10, Y: 0}, {X: 10, Y: 5},
+		{X: 5, Y: 5}, {X: 5, Y: 10}, {X: 0, Y: 10},
+	}
+	triangles := triangulate(l)
+
+	if len(triangles) != 4 {
+		t.Fatalf("got %d triangles, want 4", len(triangles))
+	}
+	if pointInTriangles(engo.Point{X: 7, Y: 7}, triangles) {
+		t.Errorf("expected the notch at (7,7) to be outside the L shape")
+	}
+	if !pointInTriangles(engo.Point{X: 2, Y: 2}, triangles) {
+		t.Errorf("expected (2,2) to be inside the L shape")
+	}
+}
+
+func TestObjectContainsRect(t *testing.T) {
+	o := &Object{Shape: ShapeRect, X: 0, Y: 0, Width: 10, Height: 10}
+
+	if !o.Contains(engo.Point{X: 5, Y: 5}) {
+		t.Errorf("expected (5,5) to be inside the rect")
+	}
+	if o.Contains(engo.Point{X: 50, Y: 50}) {
+		t.Errorf("expected (50,50) to be outside the rect")
+	}
+}
+
+func TestObjectContainsEllipse(t *testing.T) {
+	o := &Object{Shape: ShapeEllipse, X: 0, Y: 0, Width: 10, Height: 20}
+
+	if !o.Contains(engo.Point{X: 5, Y: 10}) {
+		t.Errorf("expected the ellipse's center to be inside it")
+	}
+	if o.Contains(engo.Point{X: 0, Y: 0}) {
+		t.Errorf("expected the ellipse's bounding-box corner to be outside it")
+	}
+}
+
+func TestObjectContainsPolyline(t *testing.T) {
+	o := &Object{Shape: ShapePolyline}
+	if o.Contains(engo.Point{X: 0, Y: 0}) {
+		t.Errorf("a polyline has no interior and should never contain a point")
+	}
+}
+
+func TestSetPolygonGeometry(t *testing.T) {
+	o := &Object{Shape: ShapePolygon}
+	if err := setPolygonGeometry(o, "0,0 10,0 10,10 0,10"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(o.LineBounds) != 4 {
+		t.Errorf("got %d line segments, want 4 (closed polygon)", len(o.LineBounds))
+	}
+	if !o.Contains(engo.Point{X: 5, Y: 5}) {
+		t.Errorf("expected the polygon's center to be inside it")
+	}
+}
+
+func TestObjectContainsPolygonNonOriginAnchor(t *testing.T) {
+	// Points are relative to (X,Y), so this 10x10 square's real footprint
+	// on the map is (100,100)-(110,110).
+	o := &Object{Shape: ShapePolygon, X: 100, Y: 100}
+	if err := setPolygonGeometry(o, "0,0 10,0 10,10 0,10"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !o.Contains(engo.Point{X: 105, Y: 105}) {
+		t.Errorf("expected (105,105) to be inside the translated polygon's footprint")
+	}
+	if o.Contains(engo.Point{X: 5, Y: 5}) {
+		t.Errorf("expected (5,5), inside the untranslated local points, to be outside the real footprint")
+	}
+}
@@ -0,0 +1,264 @@
+package common
+
+import (
+	"math"
+
+	"engo.io/engo"
+)
+
+// OrientationAdapter maps between TMX map coordinates (with sub-tile
+// accuracy) and display points for one of Tiled's map orientations. A
+// Level obtains its adapter from the orientation registry based on its
+// Orientation string; games that need a custom projection can register
+// their own via RegisterOrientation.
+type OrientationAdapter interface {
+	// MapToPoint maps a map coordinate to a display point, relative to the
+	// level's origin. It may mutate and return m.
+	MapToPoint(m *engo.Point) *engo.Point
+	// PointToMap is the inverse of MapToPoint. It may mutate and return p.
+	PointToMap(p *engo.Point) *engo.Point
+	// MapMaxBounds returns the point at the far corner of the map, before
+	// Level.Offset is applied.
+	MapMaxBounds() *engo.Point
+	// TileBounds returns the screen-space AABB covered by the tile at the
+	// given map row and column.
+	TileBounds(row, col int) engo.AABB
+}
+
+// orientationRegistry maps a TMX orientation name to the factory that
+// builds an OrientationAdapter for a given Level.
+var orientationRegistry = map[string]func(*Level) OrientationAdapter{}
+
+func init() {
+	RegisterOrientation("orthogonal", newOrthogonalAdapter)
+	RegisterOrientation("isometric", newIsometricAdapter)
+	RegisterOrientation("staggered", newStaggeredAdapter)
+	RegisterOrientation("hexagonal-x", newHexagonalXAdapter)
+	RegisterOrientation("hexagonal-y", newHexagonalYAdapter)
+}
+
+// RegisterOrientation registers an OrientationAdapter factory under name,
+// so that a Level whose TMX orientation attribute equals name will be
+// projected using it. Games may call this at init time to add support for
+// custom map projections.
+func RegisterOrientation(name string, factory func(*Level) OrientationAdapter) {
+	orientationRegistry[name] = factory
+}
+
+// funcOrientationAdapter implements OrientationAdapter from a pair of
+// mapping functions, which is all any of the built-in orientations need.
+type funcOrientationAdapter struct {
+	tileWidth, tileHeight float32
+	mapToPoint            func(*engo.Point) *engo.Point
+	pointToMap            func(*engo.Point) *engo.Point
+	mapMaxBounds          func() *engo.Point
+}
+
+func (a *funcOrientationAdapter) MapToPoint(m *engo.Point) *engo.Point { return a.mapToPoint(m) }
+func (a *funcOrientationAdapter) PointToMap(p *engo.Point) *engo.Point { return a.pointToMap(p) }
+func (a *funcOrientationAdapter) MapMaxBounds() *engo.Point            { return a.mapMaxBounds() }
+
+// TileBounds returns the AABB of the tile at (row, col) by mapping its
+// origin corner to screen space and extruding by one tile's width/height.
+// This is exact for orthogonal maps and a close axis-aligned approximation
+// for the projected orientations.
+func (a *funcOrientationAdapter) TileBounds(row, col int) engo.AABB {
+	p := a.mapToPoint(&engo.Point{X: float32(col), Y: float32(row)})
+	return engo.AABB{
+		Min: engo.Point{X: p.X, Y: p.Y},
+		Max: engo.Point{X: p.X + a.tileWidth, Y: p.Y + a.tileHeight},
+	}
+}
+
+func newOrthogonalAdapter(lvl *Level) OrientationAdapter {
+	tw := float32(lvl.TileWidth)
+	th := float32(lvl.TileHeight)
+
+	return &funcOrientationAdapter{
+		tileWidth:  tw,
+		tileHeight: th,
+		mapToPoint: func(m *engo.Point) *engo.Point {
+			m.X = m.X * tw
+			m.Y = m.Y * th
+			return m
+		},
+		pointToMap: func(p *engo.Point) *engo.Point {
+			p.X = p.X / tw
+			p.Y = p.Y / th
+			return p
+		},
+		mapMaxBounds: func() *engo.Point {
+			return &engo.Point{
+				X: float32(lvl.TileWidth * lvl.width),
+				Y: float32(lvl.TileHeight * lvl.height),
+			}
+		},
+	}
+}
+
+func newIsometricAdapter(lvl *Level) OrientationAdapter {
+	tw := float32(lvl.TileWidth)
+	th := float32(lvl.TileHeight)
+	hw := float32(lvl.TileWidth / 2)
+	hh := float32(lvl.TileHeight / 2)
+
+	return &funcOrientationAdapter{
+		tileWidth:  tw,
+		tileHeight: th,
+		mapToPoint: func(m *engo.Point) *engo.Point {
+			x, y := m.X, m.Y
+			m.X = (x - y) * hw
+			m.Y = (x + y) * hh
+			return m
+		},
+		pointToMap: func(p *engo.Point) *engo.Point {
+			x, y := p.X, p.Y
+			p.X = (x/hw + y/hh) / 2
+			p.Y = (y/hh - x/hw) / 2
+			return p
+		},
+		mapMaxBounds: func() *engo.Point {
+			return &engo.Point{
+				X: float32(lvl.TileWidth*lvl.width) + float32(lvl.TileWidth/2),
+				Y: float32(lvl.TileHeight/2*lvl.height) + float32(lvl.TileHeight/2),
+			}
+		},
+	}
+}
+
+func newStaggeredAdapter(lvl *Level) OrientationAdapter {
+	tw := float32(lvl.TileWidth)
+	th := float32(lvl.TileHeight)
+	hw := float32(lvl.TileWidth / 2)
+	hh := float32(lvl.TileHeight / 2)
+
+	return &funcOrientationAdapter{
+		tileWidth:  tw,
+		tileHeight: th,
+		mapToPoint: func(m *engo.Point) *engo.Point {
+			staggerX := float32(0) // no offset on even rows
+			if int(m.Y)%2 == 1 {   // odd row?
+				staggerX = hw
+			}
+			m.X = (m.X * tw) + staggerX
+			m.Y = m.Y * hh
+			return m
+		},
+		pointToMap: func(p *engo.Point) *engo.Point {
+			Y := p.Y
+			p.Y = (p.Y - p.X) / th
+			staggerX := float32(0) // no offset on even rows
+			if int(p.Y)%2 == 1 {   // odd row?
+				staggerX = hw
+			}
+			p.X = (p.X + Y - staggerX) / tw
+			return p
+		},
+		mapMaxBounds: func() *engo.Point {
+			return &engo.Point{
+				X: float32(lvl.TileWidth*lvl.width) + float32(lvl.TileWidth/2),
+				Y: float32(lvl.TileHeight/2*lvl.height) + float32(lvl.TileHeight/2),
+			}
+		},
+	}
+}
+
+// roundHexAxial rounds fractional axial hex coordinates (q, r) to the
+// nearest integer hex, preserving the cube coordinate constraint
+// x+y+z == 0 so the result lands on a real hex rather than drifting off
+// the grid. This is the standard axial->cube->round->axial recipe.
+func roundHexAxial(q, r float32) (float32, float32) {
+	x, z := q, r
+	y := -x - z
+
+	rx := float32(math.Round(float64(x)))
+	ry := float32(math.Round(float64(y)))
+	rz := float32(math.Round(float64(z)))
+
+	xDiff := math.Abs(float64(rx - x))
+	yDiff := math.Abs(float64(ry - y))
+	zDiff := math.Abs(float64(rz - z))
+
+	switch {
+	case xDiff > yDiff && xDiff > zDiff:
+		rx = -ry - rz
+	case yDiff > zDiff:
+		ry = -rx - rz
+	default:
+		rz = -rx - ry
+	}
+
+	return rx, rz
+}
+
+// newHexagonalYAdapter builds the adapter for Tiled's pointy-top hex maps
+// (staggerAxis "y"): rows stagger horizontally and map coordinates are
+// axial (q, r) hex coordinates rather than plain offsets.
+func newHexagonalYAdapter(lvl *Level) OrientationAdapter {
+	tw := float32(lvl.TileWidth)
+	th := float32(lvl.TileHeight)
+	side := float32(lvl.HexSideLength)
+	if side == 0 {
+		side = th / 2
+	}
+	rowAdvance := (th + side) / 2
+
+	return &funcOrientationAdapter{
+		tileWidth:  tw,
+		tileHeight: th,
+		mapToPoint: func(m *engo.Point) *engo.Point {
+			q, r := m.X, m.Y
+			m.X = tw*q + (tw/2)*r
+			m.Y = rowAdvance * r
+			return m
+		},
+		pointToMap: func(p *engo.Point) *engo.Point {
+			r := p.Y / rowAdvance
+			q := (p.X - (tw/2)*r) / tw
+			p.X, p.Y = roundHexAxial(q, r)
+			return p
+		},
+		mapMaxBounds: func() *engo.Point {
+			return &engo.Point{
+				X: tw*float32(lvl.width) + tw/2,
+				Y: rowAdvance * float32(lvl.height),
+			}
+		},
+	}
+}
+
+// newHexagonalXAdapter builds the adapter for Tiled's flat-top hex maps
+// (staggerAxis "x"): the transpose of newHexagonalYAdapter, columns
+// stagger vertically.
+func newHexagonalXAdapter(lvl *Level) OrientationAdapter {
+	tw := float32(lvl.TileWidth)
+	th := float32(lvl.TileHeight)
+	side := float32(lvl.HexSideLength)
+	if side == 0 {
+		side = tw / 2
+	}
+	colAdvance := (tw + side) / 2
+
+	return &funcOrientationAdapter{
+		tileWidth:  tw,
+		tileHeight: th,
+		mapToPoint: func(m *engo.Point) *engo.Point {
+			q, r := m.X, m.Y
+			m.X = colAdvance * q
+			m.Y = th*r + (th/2)*q
+			return m
+		},
+		pointToMap: func(p *engo.Point) *engo.Point {
+			q := p.X / colAdvance
+			r := (p.Y - (th/2)*q) / th
+			p.X, p.Y = roundHexAxial(q, r)
+			return p
+		},
+		mapMaxBounds: func() *engo.Point {
+			return &engo.Point{
+				X: colAdvance * float32(lvl.width),
+				Y: th*float32(lvl.height) + th/2,
+			}
+		},
+	}
+}
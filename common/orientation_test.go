@@ -0,0 +1,61 @@
+package common
+
+import (
+	"math"
+	"testing"
+
+	"engo.io/engo"
+)
+
+func roundTrip(t *testing.T, lvl *Level, mapPoint engo.Point) {
+	t.Helper()
+
+	factory, ok := orientationRegistry[lvl.Orientation]
+	if !ok {
+		t.Fatalf("no adapter registered for orientation %q", lvl.Orientation)
+	}
+	adapter := factory(lvl)
+
+	screen := adapter.MapToPoint(&engo.Point{X: mapPoint.X, Y: mapPoint.Y})
+	got := adapter.PointToMap(&engo.Point{X: screen.X, Y: screen.Y})
+
+	const epsilon = 1e-3
+	if math.Abs(float64(got.X-mapPoint.X)) > epsilon || math.Abs(float64(got.Y-mapPoint.Y)) > epsilon {
+		t.Errorf("round trip of %v through %q got %v, want %v (screen point was %v)",
+			mapPoint, lvl.Orientation, got, mapPoint, screen)
+	}
+}
+
+func TestOrthogonalRoundTrip(t *testing.T) {
+	lvl := &Level{Orientation: "orthogonal", TileWidth: 32, TileHeight: 32}
+	roundTrip(t, lvl, engo.Point{X: 3, Y: 5})
+}
+
+func TestIsometricMapToPoint(t *testing.T) {
+	lvl := &Level{Orientation: "isometric", TileWidth: 64, TileHeight: 32}
+	adapter := orientationRegistry["isometric"](lvl)
+
+	screen := adapter.MapToPoint(&engo.Point{X: 3, Y: 2})
+	if screen.X != 32 || screen.Y != 80 {
+		t.Errorf("got screen point %v, want (32, 80)", screen)
+	}
+}
+
+func TestIsometricRoundTrip(t *testing.T) {
+	lvl := &Level{Orientation: "isometric", TileWidth: 64, TileHeight: 32}
+	roundTrip(t, lvl, engo.Point{X: 3, Y: 2})
+	roundTrip(t, lvl, engo.Point{X: 0, Y: 0})
+	roundTrip(t, lvl, engo.Point{X: 7, Y: 1})
+}
+
+func TestHexagonalYRoundTrip(t *testing.T) {
+	lvl := &Level{Orientation: "hexagonal-y", TileWidth: 32, TileHeight: 28, HexSideLength: 8}
+	roundTrip(t, lvl, engo.Point{X: 2, Y: -1})
+	roundTrip(t, lvl, engo.Point{X: -3, Y: 4})
+}
+
+func TestHexagonalXRoundTrip(t *testing.T) {
+	lvl := &Level{Orientation: "hexagonal-x", TileWidth: 28, TileHeight: 32, HexSideLength: 8}
+	roundTrip(t, lvl, engo.Point{X: 2, Y: -1})
+	roundTrip(t, lvl, engo.Point{X: -3, Y: 4})
+}
@@ -0,0 +1,290 @@
+package common
+
+import (
+	"container/heap"
+	"fmt"
+	"math"
+	"strconv"
+
+	"engo.io/engo"
+)
+
+// PathOptions configures TileLayer.FindPath.
+type PathOptions struct {
+	// Diagonal allows 8-way movement on orthogonal/isometric layers. It is
+	// ignored on hexagonal layers (always 6-way) and staggered layers
+	// (which already move diagonally by nature of the grid).
+	Diagonal bool
+	// MaxNodes caps the number of nodes FindPath will expand before giving
+	// up, guarding against runaway searches on large maps. Zero means
+	// unlimited.
+	MaxNodes int
+	// Blockers is consulted alongside the receiving TileLayer itself: a
+	// tile is blocked if it, or the tile at the same coordinate on any
+	// layer here, reports !IsWalkable(). Pass e.g. a collision layer
+	// separate from the visual ground layer the path is computed over.
+	Blockers []*TileLayer
+	// Blocked, if set, overrides the default IsWalkable-based blocking
+	// rule (and Blockers) entirely. It is called with map coordinates and
+	// should return true if that tile cannot be entered.
+	Blocked func(x, y int) bool
+}
+
+// pathNode identifies a tile by its integer map coordinates.
+type pathNode struct {
+	x, y int
+}
+
+// FindPath runs A* across l's grid from `from` to `to` (map coordinates),
+// treating a tile as blocked if it, or the tile at the same coordinate in
+// any of blockers, reports !IsWalkable(). The returned path is the list of
+// map coordinates from `from` to `to`, inclusive of both ends.
+func (l *TileLayer) FindPath(from, to engo.Point, opts PathOptions) ([]engo.Point, error) {
+	start := pathNode{int(from.X), int(from.Y)}
+	goal := pathNode{int(to.X), int(to.Y)}
+
+	layers := append([]*TileLayer{l}, opts.Blockers...)
+	blocked := opts.Blocked
+	if blocked == nil {
+		blocked = func(x, y int) bool {
+			for _, b := range layers {
+				t := b.tileAt(x, y)
+				if t != nil && !t.IsWalkable() {
+					return true
+				}
+			}
+			return false
+		}
+	}
+
+	neighbors := l.pathNeighbors(opts.Diagonal)
+	heuristic := l.pathHeuristic(opts.Diagonal)
+
+	open := &pathQueue{}
+	heap.Init(open)
+	heap.Push(open, &pathQueueEntry{node: start, f: heuristic(start, goal)})
+
+	cameFrom := map[pathNode]pathNode{}
+	gScore := map[pathNode]float64{start: 0}
+	visited := 0
+
+	for open.Len() > 0 {
+		current := heap.Pop(open).(*pathQueueEntry).node
+		if current == goal {
+			return l.reconstructPath(cameFrom, current), nil
+		}
+
+		visited++
+		if opts.MaxNodes > 0 && visited > opts.MaxNodes {
+			return nil, fmt.Errorf("common: FindPath exceeded MaxNodes (%d)", opts.MaxNodes)
+		}
+
+		for _, n := range neighbors(current) {
+			if n.x < 0 || n.y < 0 || n.x >= l.Width || n.y >= l.Height {
+				continue
+			}
+			if blocked(n.x, n.y) {
+				continue
+			}
+
+			tentative := gScore[current] + l.pathStepCost(n)
+			if g, ok := gScore[n]; !ok || tentative < g {
+				cameFrom[n] = current
+				gScore[n] = tentative
+				heap.Push(open, &pathQueueEntry{node: n, f: tentative + heuristic(n, goal)})
+			}
+		}
+	}
+
+	return nil, fmt.Errorf("common: no path from %v to %v", from, to)
+}
+
+// LineOfSight walks a Bresenham line in map coordinates from a to b and
+// returns false as soon as it crosses a non-walkable tile, true if it
+// reaches b unobstructed. Useful for AI sight checks on the same
+// walkability data FindPath uses.
+func (l *TileLayer) LineOfSight(a, b engo.Point) bool {
+	x0, y0 := int(a.X), int(a.Y)
+	x1, y1 := int(b.X), int(b.Y)
+
+	dx := int(math.Abs(float64(x1 - x0)))
+	dy := -int(math.Abs(float64(y1 - y0)))
+	sx, sy := 1, 1
+	if x0 > x1 {
+		sx = -1
+	}
+	if y0 > y1 {
+		sy = -1
+	}
+	err := dx + dy
+
+	for {
+		if t := l.tileAt(x0, y0); t != nil && !t.IsWalkable() {
+			return false
+		}
+		if x0 == x1 && y0 == y1 {
+			return true
+		}
+		e2 := 2 * err
+		if e2 >= dy {
+			err += dy
+			x0 += sx
+		}
+		if e2 <= dx {
+			err += dx
+			y0 += sy
+		}
+	}
+}
+
+// tileAt returns the tile at map coordinate (x, y), or nil if it is out of
+// bounds.
+func (l *TileLayer) tileAt(x, y int) *tile {
+	if x < 0 || y < 0 || x >= l.Width || y >= l.Height {
+		return nil
+	}
+	idx := x + y*l.Width
+	if idx < 0 || idx >= len(l.Tiles) {
+		return nil
+	}
+	return l.Tiles[idx]
+}
+
+// pathStepCost is the cost of moving into node n: 1 plus the tile's
+// optional "cost" property.
+func (l *TileLayer) pathStepCost(n pathNode) float64 {
+	cost := 1.0
+	t := l.tileAt(n.x, n.y)
+	if t == nil {
+		return cost
+	}
+	if p, ok := t.Properties["cost"]; ok {
+		if v, err := strconv.ParseFloat(p.Value, 64); err == nil {
+			cost += v
+		}
+	}
+	return cost
+}
+
+// orthogonalDirs are the four axis-aligned neighbor offsets.
+var orthogonalDirs = []pathNode{{1, 0}, {-1, 0}, {0, 1}, {0, -1}}
+
+// diagonalDirs are the four diagonal neighbor offsets.
+var diagonalDirs = []pathNode{{1, 1}, {1, -1}, {-1, 1}, {-1, -1}}
+
+// hexAxialDirs are the six axial hex neighbor offsets; these are the same
+// for both the hexagonal-x and hexagonal-y orientations since map
+// coordinates on a hex TileLayer are always axial (q, r).
+var hexAxialDirs = []pathNode{{1, 0}, {1, -1}, {0, -1}, {-1, 0}, {-1, 1}, {0, 1}}
+
+// pathNeighbors returns the neighbor-generating function appropriate for
+// l's orientation.
+func (l *TileLayer) pathNeighbors(diagonal bool) func(pathNode) []pathNode {
+	switch l.Orientation {
+	case "hexagonal-x", "hexagonal-y":
+		return func(n pathNode) []pathNode {
+			out := make([]pathNode, len(hexAxialDirs))
+			for i, d := range hexAxialDirs {
+				out[i] = pathNode{n.x + d.x, n.y + d.y}
+			}
+			return out
+		}
+	case "staggered":
+		return func(n pathNode) []pathNode {
+			// Staggered rows shift the effective diagonal x-offset
+			// depending on row parity, same as the MapToPoint built by
+			// newStaggeredAdapter.
+			dx := -1
+			if n.y%2 != 0 {
+				dx = 1
+			}
+			dirs := []pathNode{
+				{1, 0}, {-1, 0}, {0, 1}, {0, -1},
+				{dx, 1}, {dx, -1},
+			}
+			out := make([]pathNode, len(dirs))
+			for i, d := range dirs {
+				out[i] = pathNode{n.x + d.x, n.y + d.y}
+			}
+			return out
+		}
+	default:
+		dirs := orthogonalDirs
+		if diagonal {
+			dirs = append(append([]pathNode{}, orthogonalDirs...), diagonalDirs...)
+		}
+		return func(n pathNode) []pathNode {
+			out := make([]pathNode, len(dirs))
+			for i, d := range dirs {
+				out[i] = pathNode{n.x + d.x, n.y + d.y}
+			}
+			return out
+		}
+	}
+}
+
+// pathHeuristic returns the admissible heuristic appropriate for l's
+// orientation: hex distance on hex grids, Chebyshev distance on 8-way
+// orthogonal/isometric/staggered grids (matching pathStepCost, which charges
+// the same cost for a diagonal step as an orthogonal one), Manhattan
+// distance otherwise.
+func (l *TileLayer) pathHeuristic(diagonal bool) func(a, b pathNode) float64 {
+	switch l.Orientation {
+	case "hexagonal-x", "hexagonal-y":
+		return func(a, b pathNode) float64 {
+			dq := float64(a.x - b.x)
+			dr := float64(a.y - b.y)
+			return (math.Abs(dq) + math.Abs(dr) + math.Abs(dq+dr)) / 2
+		}
+	case "staggered":
+		diagonal = true
+		fallthrough
+	default:
+		if !diagonal {
+			return func(a, b pathNode) float64 {
+				return math.Abs(float64(a.x-b.x)) + math.Abs(float64(a.y-b.y))
+			}
+		}
+		return func(a, b pathNode) float64 {
+			dx := math.Abs(float64(a.x - b.x))
+			dy := math.Abs(float64(a.y - b.y))
+			return math.Max(dx, dy)
+		}
+	}
+}
+
+// reconstructPath walks cameFrom back from current to the start node and
+// returns the path in start->goal order.
+func (l *TileLayer) reconstructPath(cameFrom map[pathNode]pathNode, current pathNode) []engo.Point {
+	path := []engo.Point{{X: float32(current.x), Y: float32(current.y)}}
+	for {
+		prev, ok := cameFrom[current]
+		if !ok {
+			break
+		}
+		current = prev
+		path = append([]engo.Point{{X: float32(current.x), Y: float32(current.y)}}, path...)
+	}
+	return path
+}
+
+// pathQueueEntry is one entry in the A* open set, ordered by f-score.
+type pathQueueEntry struct {
+	node pathNode
+	f    float64
+}
+
+// pathQueue is a container/heap.Interface min-heap of pathQueueEntry.
+type pathQueue []*pathQueueEntry
+
+func (q pathQueue) Len() int            { return len(q) }
+func (q pathQueue) Less(i, j int) bool  { return q[i].f < q[j].f }
+func (q pathQueue) Swap(i, j int)       { q[i], q[j] = q[j], q[i] }
+func (q *pathQueue) Push(x interface{}) { *q = append(*q, x.(*pathQueueEntry)) }
+func (q *pathQueue) Pop() interface{} {
+	old := *q
+	n := len(old)
+	item := old[n-1]
+	*q = old[:n-1]
+	return item
+}
@@ -0,0 +1,84 @@
+package common
+
+import (
+	"testing"
+
+	"engo.io/engo"
+)
+
+// newTestGrid builds a w x h orthogonal TileLayer where tiles listed in
+// blocked (as y*w+x indices) are not walkable.
+func newTestGrid(w, h int, blocked map[int]bool) *TileLayer {
+	lvl := &Level{Orientation: "orthogonal", TileWidth: 16, TileHeight: 16}
+	tiles := make([]*tile, w*h)
+	for i := range tiles {
+		props := map[string]Property{}
+		if !blocked[i] {
+			props["walkable"] = Property{Value: "true"}
+		}
+		tiles[i] = &tile{Properties: props}
+	}
+	return &TileLayer{Width: w, Height: h, Tiles: tiles, Level: lvl}
+}
+
+func TestFindPathStraightLine(t *testing.T) {
+	layer := newTestGrid(5, 1, nil)
+
+	path, err := layer.FindPath(engo.Point{X: 0, Y: 0}, engo.Point{X: 4, Y: 0}, PathOptions{})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(path) != 5 {
+		t.Fatalf("got path length %d, want 5: %v", len(path), path)
+	}
+}
+
+func TestFindPathAroundWall(t *testing.T) {
+	// 3x3 grid with the middle column blocked except the bottom row.
+	blocked := map[int]bool{1: true, 4: true}
+	layer := newTestGrid(3, 3, blocked)
+
+	path, err := layer.FindPath(engo.Point{X: 0, Y: 0}, engo.Point{X: 2, Y: 0}, PathOptions{})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(path) == 0 {
+		t.Fatalf("expected a non-empty path around the wall")
+	}
+}
+
+func TestFindPathNoRoute(t *testing.T) {
+	blocked := map[int]bool{1: true, 4: true, 7: true}
+	layer := newTestGrid(3, 3, blocked)
+
+	if _, err := layer.FindPath(engo.Point{X: 0, Y: 0}, engo.Point{X: 2, Y: 0}, PathOptions{}); err == nil {
+		t.Fatalf("expected an error when no path exists")
+	}
+}
+
+func TestFindPathDiagonalShortcut(t *testing.T) {
+	// Open 5x5 grid: diagonal moves cost the same as orthogonal ones (see
+	// pathStepCost), so the optimal route from corner to corner is 4
+	// diagonal steps (5 nodes), not an 8-step orthogonal-only route.
+	layer := newTestGrid(5, 5, nil)
+
+	path, err := layer.FindPath(engo.Point{X: 0, Y: 0}, engo.Point{X: 4, Y: 4}, PathOptions{Diagonal: true})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(path) != 5 {
+		t.Fatalf("got path length %d, want 5 (the all-diagonal shortest route): %v", len(path), path)
+	}
+}
+
+func TestLineOfSightBlocked(t *testing.T) {
+	blocked := map[int]bool{2: true}
+	layer := newTestGrid(5, 1, blocked)
+
+	if layer.LineOfSight(engo.Point{X: 0, Y: 0}, engo.Point{X: 4, Y: 0}) {
+		t.Fatalf("expected line of sight to be blocked by tile at x=2")
+	}
+	if !layer.LineOfSight(engo.Point{X: 0, Y: 0}, engo.Point{X: 1, Y: 0}) {
+		t.Fatalf("expected clear line of sight over unblocked tiles")
+	}
+}
@@ -0,0 +1,141 @@
+package common
+
+import (
+	"bytes"
+	"compress/gzip"
+	"compress/zlib"
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/xml"
+	"fmt"
+	"io/ioutil"
+	"strconv"
+	"strings"
+)
+
+// Tiled reserves the top three bits of a tile GID to record how the tile
+// should be flipped/rotated when drawn. The remaining bits are the actual
+// tileset-global id.
+const (
+	flipHorizontalFlag uint32 = 0x80000000
+	flipVerticalFlag   uint32 = 0x40000000
+	flipDiagonalFlag   uint32 = 0x20000000
+	flipFlagsMask      uint32 = flipHorizontalFlag | flipVerticalFlag | flipDiagonalFlag
+)
+
+// xmlTileData mirrors the handful of attributes a raw (uncompressed,
+// non-base64) TMX <data> payload can carry: one <tile gid="..."/> child per
+// map cell.
+type xmlTileData struct {
+	Tiles []struct {
+		GID uint32 `xml:"gid,attr"`
+	} `xml:"tile"`
+}
+
+// DecodeLayerData decodes a TMX layer's <data> payload into the flat,
+// row-major slice of GIDs it represents. encoding and compression come
+// straight off the <data encoding="..." compression="..."> attributes;
+// either may be empty, matching what Tiled emits for the default (raw XML)
+// case.
+//
+// GIDs are returned with Tiled's flip flags still set in the top three
+// bits; callers that need the bare tileset id should run the result
+// through SplitGID.
+func DecodeLayerData(encoding, compression string, raw []byte) ([]uint32, error) {
+	switch encoding {
+	case "csv":
+		return decodeCSVLayerData(raw)
+	case "base64":
+		return decodeBase64LayerData(compression, raw)
+	case "":
+		return decodeXMLLayerData(raw)
+	default:
+		return nil, fmt.Errorf("tmx: unsupported layer data encoding %q", encoding)
+	}
+}
+
+func decodeCSVLayerData(raw []byte) ([]uint32, error) {
+	fields := strings.Split(strings.TrimSpace(string(raw)), ",")
+	gids := make([]uint32, 0, len(fields))
+	for _, f := range fields {
+		f = strings.TrimSpace(f)
+		if f == "" {
+			continue
+		}
+		v, err := strconv.ParseUint(f, 10, 32)
+		if err != nil {
+			return nil, fmt.Errorf("tmx: invalid csv gid %q: %v", f, err)
+		}
+		gids = append(gids, uint32(v))
+	}
+	return gids, nil
+}
+
+func decodeXMLLayerData(raw []byte) ([]uint32, error) {
+	var data xmlTileData
+	// raw is the inner XML of <data>...</data>, so wrap it in a synthetic
+	// root element before handing it to encoding/xml.
+	wrapped := append(append([]byte("<data>"), raw...), []byte("</data>")...)
+	if err := xml.Unmarshal(wrapped, &data); err != nil {
+		return nil, fmt.Errorf("tmx: invalid xml tile data: %v", err)
+	}
+	gids := make([]uint32, len(data.Tiles))
+	for i, t := range data.Tiles {
+		gids[i] = t.GID
+	}
+	return gids, nil
+}
+
+func decodeBase64LayerData(compression string, raw []byte) ([]uint32, error) {
+	decoded, err := base64.StdEncoding.DecodeString(strings.TrimSpace(string(raw)))
+	if err != nil {
+		return nil, fmt.Errorf("tmx: invalid base64 layer data: %v", err)
+	}
+
+	switch compression {
+	case "":
+		// uncompressed
+	case "gzip":
+		r, err := gzip.NewReader(bytes.NewReader(decoded))
+		if err != nil {
+			return nil, fmt.Errorf("tmx: invalid gzip layer data: %v", err)
+		}
+		defer r.Close()
+		if decoded, err = ioutil.ReadAll(r); err != nil {
+			return nil, fmt.Errorf("tmx: invalid gzip layer data: %v", err)
+		}
+	case "zlib":
+		r, err := zlib.NewReader(bytes.NewReader(decoded))
+		if err != nil {
+			return nil, fmt.Errorf("tmx: invalid zlib layer data: %v", err)
+		}
+		defer r.Close()
+		if decoded, err = ioutil.ReadAll(r); err != nil {
+			return nil, fmt.Errorf("tmx: invalid zlib layer data: %v", err)
+		}
+	default:
+		return nil, fmt.Errorf("tmx: unsupported layer data compression %q", compression)
+	}
+
+	if len(decoded)%4 != 0 {
+		return nil, fmt.Errorf("tmx: layer data length %d is not a multiple of 4", len(decoded))
+	}
+
+	gids := make([]uint32, len(decoded)/4)
+	for i := range gids {
+		gids[i] = binary.LittleEndian.Uint32(decoded[i*4 : i*4+4])
+	}
+	return gids, nil
+}
+
+// SplitGID strips Tiled's flip flags off of a raw GID, returning the bare
+// tileset-global id plus whether the tile should be drawn flipped
+// horizontally, vertically and/or diagonally (the combination Tiled uses to
+// express 90-degree rotations).
+func SplitGID(gid uint32) (id uint32, flipHorizontal, flipVertical, flipDiagonal bool) {
+	flipHorizontal = gid&flipHorizontalFlag != 0
+	flipVertical = gid&flipVerticalFlag != 0
+	flipDiagonal = gid&flipDiagonalFlag != 0
+	id = gid &^ flipFlagsMask
+	return
+}
@@ -0,0 +1,69 @@
+package common
+
+import (
+	"bytes"
+	"compress/zlib"
+	"encoding/base64"
+	"reflect"
+	"testing"
+)
+
+func TestDecodeLayerDataCSV(t *testing.T) {
+	gids, err := DecodeLayerData("csv", "", []byte("1,2,0,3\n"))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	want := []uint32{1, 2, 0, 3}
+	if !reflect.DeepEqual(gids, want) {
+		t.Errorf("got %v, want %v", gids, want)
+	}
+}
+
+func TestDecodeLayerDataBase64Zlib(t *testing.T) {
+	want := []uint32{1, 0, 2, 5}
+
+	var buf bytes.Buffer
+	zw := zlib.NewWriter(&buf)
+	for _, gid := range want {
+		raw := []byte{byte(gid), byte(gid >> 8), byte(gid >> 16), byte(gid >> 24)}
+		if _, err := zw.Write(raw); err != nil {
+			t.Fatalf("unexpected error writing zlib payload: %v", err)
+		}
+	}
+	if err := zw.Close(); err != nil {
+		t.Fatalf("unexpected error closing zlib writer: %v", err)
+	}
+
+	encoded := base64.StdEncoding.EncodeToString(buf.Bytes())
+
+	gids, err := DecodeLayerData("base64", "zlib", []byte(encoded))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !reflect.DeepEqual(gids, want) {
+		t.Errorf("got %v, want %v", gids, want)
+	}
+}
+
+func TestDecodeLayerDataXML(t *testing.T) {
+	gids, err := DecodeLayerData("", "", []byte(`<tile gid="1"/><tile gid="0"/><tile gid="4"/>`))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	want := []uint32{1, 0, 4}
+	if !reflect.DeepEqual(gids, want) {
+		t.Errorf("got %v, want %v", gids, want)
+	}
+}
+
+func TestSplitGID(t *testing.T) {
+	gid := uint32(5) | flipHorizontalFlag | flipDiagonalFlag
+
+	id, h, v, d := SplitGID(gid)
+	if id != 5 {
+		t.Errorf("got id %v, want 5", id)
+	}
+	if !h || v || !d {
+		t.Errorf("got flip flags h=%v v=%v d=%v, want h=true v=false d=true", h, v, d)
+	}
+}